@@ -0,0 +1,120 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/auth"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Action = (*AddLiquidity)(nil)
+
+// AddLiquidity deposits up to [AmountIn]/[AmountOut] into an existing pool
+// and mints LP shares proportional to min(dx/reserveIn, dy/reserveOut); only
+// the amounts matching the pool's ratio at that share count are taken.
+type AddLiquidity struct {
+	In        ids.ID `json:"in"`
+	Out       ids.ID `json:"out"`
+	AmountIn  uint64 `json:"amountIn"`
+	AmountOut uint64 `json:"amountOut"`
+}
+
+func (a *AddLiquidity) StateKeys(rauth chain.Auth, _ ids.ID) [][]byte {
+	actor := auth.GetActor(rauth)
+	pairID := PairID(a.In, a.Out)
+	return [][]byte{
+		storage.PrefixBalanceKey(actor, a.In),
+		storage.PrefixBalanceKey(actor, a.Out),
+		storage.PoolKey(pairID),
+		storage.PrefixLPBalanceKey(actor, pairID),
+	}
+}
+
+func (a *AddLiquidity) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	db chain.Database,
+	_ int64,
+	rauth chain.Auth,
+	_ ids.ID,
+) (*chain.Result, error) {
+	actor := auth.GetActor(rauth)
+	unitsUsed := a.MaxUnits(r)
+	if a.AmountIn == 0 || a.AmountOut == 0 {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: OutputSupplyZero}, nil
+	}
+	pairID := PairID(a.In, a.Out)
+	reserveIn, reserveOut, totalShares, exists, err := storage.GetPool(ctx, db, pairID)
+	if err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+	}
+	// A pool whose shares were all removed has zero reserves and shares, which
+	// mulDiv below can't divide by; treat it as missing.
+	if !exists || totalShares == 0 || reserveIn == 0 || reserveOut == 0 {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: OutputPoolMissing}, nil
+	}
+	// AmountIn*totalShares (and AmountOut*totalShares) can overflow uint64
+	// at realistic reserve sizes, so the intermediate product goes through
+	// mulDiv rather than bare uint64 multiplication.
+	sharesIn := mulDiv(a.AmountIn, totalShares, reserveIn)
+	sharesOut := mulDiv(a.AmountOut, totalShares, reserveOut)
+	shares := sharesIn
+	if sharesOut < shares {
+		shares = sharesOut
+	}
+	if shares == 0 {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: OutputInsufficientShares}, nil
+	}
+	// [shares] is minted against whichever side is the binding constraint;
+	// pull back the actual amounts to match that ratio so a caller who
+	// over-supplies the other side gets the excess left in their balance
+	// instead of donating it to existing LPs.
+	amountIn := mulDiv(shares, reserveIn, totalShares)
+	amountOut := mulDiv(shares, reserveOut, totalShares)
+	if err := storage.SubBalance(ctx, db, actor, a.In, amountIn); err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+	}
+	if err := storage.SubBalance(ctx, db, actor, a.Out, amountOut); err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+	}
+	if err := storage.SetPool(ctx, db, pairID, reserveIn+amountIn, reserveOut+amountOut, totalShares+shares); err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+	}
+	if err := storage.AddLPBalance(ctx, db, actor, pairID, shares); err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+	}
+	return &chain.Result{Success: true, Units: unitsUsed}, nil
+}
+
+func (*AddLiquidity) MaxUnits(chain.Rules) uint64 {
+	return consts.IDLen*2 + consts.Uint64Len*2
+}
+
+func (a *AddLiquidity) Marshal(p *codec.Packer) {
+	p.PackID(a.In)
+	p.PackID(a.Out)
+	p.PackUint64(a.AmountIn)
+	p.PackUint64(a.AmountOut)
+}
+
+func UnmarshalAddLiquidity(p *codec.Packer) (chain.Action, error) {
+	var add AddLiquidity
+	p.UnpackID(false, &add.In)
+	p.UnpackID(false, &add.Out)
+	add.AmountIn = p.UnpackUint64(true)
+	add.AmountOut = p.UnpackUint64(true)
+	return &add, p.Err()
+}
+
+func (*AddLiquidity) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
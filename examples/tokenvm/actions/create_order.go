@@ -12,6 +12,7 @@ import (
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/consts"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/auth"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/orderbook"
 	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
 	"github.com/ava-labs/hypersdk/utils"
 )
@@ -39,6 +40,12 @@ type CreateOrder struct {
 	// TODO: ensure supply is a multiple of OutRate
 	Supply uint64 `json:"supply"`
 
+	// [Expiry] is the unix second after which anyone may submit an
+	// ExpireOrder to refund the remaining [Supply] to the creator. A value
+	// of 0 means the order never expires and must be filled or cancelled
+	// explicitly.
+	Expiry int64 `json:"expiry"`
+
 	// Notes:
 	// * Users are allowed to have any number of orders for the same [In]-[Out] pair.
 	// * Using [InRate] and [OutRate] blocks ensures we avoid any odd rounding
@@ -57,12 +64,15 @@ func (c *CreateOrder) Execute(
 	ctx context.Context,
 	r chain.Rules,
 	db chain.Database,
-	_ int64,
+	timestamp int64,
 	rauth chain.Auth,
 	txID ids.ID,
 ) (*chain.Result, error) {
 	actor := auth.GetActor(rauth)
 	unitsUsed := c.MaxUnits(r) // max units == units
+	if c.Expiry < 0 || (c.Expiry != 0 && c.Expiry <= timestamp) {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: OutputExpiryInvalid}, nil
+	}
 	if c.InRate == 0 {
 		return &chain.Result{Success: false, Units: unitsUsed, Output: OutputInRateZero}, nil
 	}
@@ -78,16 +88,29 @@ func (c *CreateOrder) Execute(
 	if err := storage.SubBalance(ctx, db, actor, c.Out, c.Supply); err != nil {
 		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
 	}
-	if err := storage.SetOrder(ctx, db, txID, c.In, c.InRate, c.Out, c.OutRate, c.Supply, actor); err != nil {
+	if err := storage.SetOrder(ctx, db, txID, c.In, c.InRate, c.Out, c.OutRate, c.Supply, actor, c.Expiry); err != nil {
 		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
 	}
+	// Staged rather than published immediately: the VM commits it once this
+	// tx's block is actually accepted, so a block that's verified but
+	// rejected (or re-executed) never leaks a phantom create to subscribers.
+	orderbook.DefaultHub.StageCreate(txID, PairID(c.In, c.Out), orderbook.Order{
+		ID:      txID,
+		In:      c.In,
+		InRate:  c.InRate,
+		Out:     c.Out,
+		OutRate: c.OutRate,
+		Supply:  c.Supply,
+		Owner:   actor,
+		Expiry:  c.Expiry,
+	})
 	return &chain.Result{Success: true, Units: unitsUsed}, nil
 }
 
 func (*CreateOrder) MaxUnits(chain.Rules) uint64 {
 	// We use size as the price of this transaction but we could just as easily
 	// use any other calculation.
-	return consts.IDLen*2 + consts.Uint64Len*3
+	return consts.IDLen*2 + consts.Uint64Len*4
 }
 
 func (c *CreateOrder) Marshal(p *codec.Packer) {
@@ -96,6 +119,7 @@ func (c *CreateOrder) Marshal(p *codec.Packer) {
 	p.PackID(c.Out)
 	p.PackUint64(c.OutRate)
 	p.PackUint64(c.Supply)
+	p.PackInt64(c.Expiry)
 }
 
 func UnmarshalCreateOrder(p *codec.Packer) (chain.Action, error) {
@@ -105,6 +129,7 @@ func UnmarshalCreateOrder(p *codec.Packer) (chain.Action, error) {
 	p.UnpackID(false, &create.Out) // empty ID is the native asset
 	create.OutRate = p.UnpackUint64(true)
 	create.Supply = p.UnpackUint64(true)
+	create.Expiry = p.UnpackInt64(false) // 0 means the order never expires
 	return &create, p.Err()
 }
 
@@ -0,0 +1,114 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/auth"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Action = (*CreatePool)(nil)
+
+// CreatePool seeds a constant-product pool for the (In, Out) pair with
+// initial reserves deposited by the creator and mints the first LP shares to
+// them. It fails if a pool with outstanding shares already exists for this
+// pair; use AddLiquidity to contribute to an existing pool. A pool whose
+// shares were all removed can be seeded again.
+type CreatePool struct {
+	// [In] is one asset of the pair.
+	In ids.ID `json:"in"`
+
+	// [Out] is the other asset of the pair.
+	Out ids.ID `json:"out"`
+
+	// [AmountIn] is the initial reserve of [In] deposited by the creator.
+	AmountIn uint64 `json:"amountIn"`
+
+	// [AmountOut] is the initial reserve of [Out] deposited by the creator.
+	AmountOut uint64 `json:"amountOut"`
+}
+
+func (c *CreatePool) StateKeys(rauth chain.Auth, _ ids.ID) [][]byte {
+	actor := auth.GetActor(rauth)
+	pairID := PairID(c.In, c.Out)
+	return [][]byte{
+		storage.PrefixBalanceKey(actor, c.In),
+		storage.PrefixBalanceKey(actor, c.Out),
+		storage.PoolKey(pairID),
+		storage.PrefixLPBalanceKey(actor, pairID),
+	}
+}
+
+func (c *CreatePool) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	db chain.Database,
+	_ int64,
+	rauth chain.Auth,
+	_ ids.ID,
+) (*chain.Result, error) {
+	actor := auth.GetActor(rauth)
+	unitsUsed := c.MaxUnits(r)
+	if c.AmountIn == 0 || c.AmountOut == 0 {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: OutputSupplyZero}, nil
+	}
+	pairID := PairID(c.In, c.Out)
+	if _, _, totalShares, exists, err := storage.GetPool(ctx, db, pairID); err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+	} else if exists && totalShares != 0 {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: OutputPoolExists}, nil
+	}
+	if err := storage.SubBalance(ctx, db, actor, c.In, c.AmountIn); err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+	}
+	if err := storage.SubBalance(ctx, db, actor, c.Out, c.AmountOut); err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+	}
+	// The initial LP mint is the geometric mean of the deposited reserves, in
+	// keeping with Uniswap v2's bootstrap formula. AmountIn*AmountOut can
+	// overflow uint64, so the product is computed wide before the root.
+	shares := isqrtProduct(c.AmountIn, c.AmountOut)
+	if shares == 0 {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: OutputSupplyZero}, nil
+	}
+	if err := storage.SetPool(ctx, db, pairID, c.AmountIn, c.AmountOut, shares); err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+	}
+	if err := storage.AddLPBalance(ctx, db, actor, pairID, shares); err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+	}
+	return &chain.Result{Success: true, Units: unitsUsed}, nil
+}
+
+func (*CreatePool) MaxUnits(chain.Rules) uint64 {
+	return consts.IDLen*2 + consts.Uint64Len*2
+}
+
+func (c *CreatePool) Marshal(p *codec.Packer) {
+	p.PackID(c.In)
+	p.PackID(c.Out)
+	p.PackUint64(c.AmountIn)
+	p.PackUint64(c.AmountOut)
+}
+
+func UnmarshalCreatePool(p *codec.Packer) (chain.Action, error) {
+	var create CreatePool
+	p.UnpackID(false, &create.In)
+	p.UnpackID(false, &create.Out)
+	create.AmountIn = p.UnpackUint64(true)
+	create.AmountOut = p.UnpackUint64(true)
+	return &create, p.Err()
+}
+
+func (*CreatePool) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
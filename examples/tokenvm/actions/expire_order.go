@@ -0,0 +1,120 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/auth"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/orderbook"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Action = (*ExpireOrder)(nil)
+
+// ExpireOrder lets anyone sweep an order whose [Expiry] has passed: the
+// remaining [Supply] is refunded to the order's original creator and a
+// bounty (set by [chain.Rules]) is paid to the caller out of that refund.
+type ExpireOrder struct {
+	// [Order] is the txID that created the order being expired.
+	Order ids.ID `json:"order"`
+
+	// [Owner] is the order creator. It is required so StateKeys can reserve
+	// a write lock on the refund's balance key without reading the order
+	// first; Execute rejects the tx if it doesn't match the stored order.
+	Owner crypto.PublicKey `json:"owner"`
+
+	// [Out] is the asset the order is denominated in and the one the refund
+	// and bounty are paid out of, again required so StateKeys can be
+	// computed up front.
+	Out ids.ID `json:"out"`
+}
+
+func (e *ExpireOrder) StateKeys(rauth chain.Auth, _ ids.ID) [][]byte {
+	actor := auth.GetActor(rauth)
+	return [][]byte{
+		storage.PrefixOrderKey(e.Order),
+		storage.PrefixBalanceKey(e.Owner, e.Out),
+		storage.PrefixBalanceKey(actor, e.Out),
+	}
+}
+
+func (e *ExpireOrder) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	db chain.Database,
+	timestamp int64,
+	rauth chain.Auth,
+	txID ids.ID,
+) (*chain.Result, error) {
+	actor := auth.GetActor(rauth)
+	unitsUsed := e.MaxUnits(r)
+	in, inRate, out, outRate, supply, owner, expiry, err := storage.GetOrder(ctx, db, e.Order)
+	if err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: OutputOrderMissing}, nil
+	}
+	if expiry == 0 || timestamp < expiry {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: OutputOrderNotExpired}, nil
+	}
+	if out != e.Out || owner != e.Owner {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: OutputOrderMissing}, nil
+	}
+	bounty := (supply * expireOrderBountyPercent(r)) / 100
+	refund := supply - bounty
+	if refund > 0 {
+		if err := storage.AddBalance(ctx, db, owner, out, refund); err != nil {
+			return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+		}
+	}
+	if bounty > 0 {
+		if err := storage.AddBalance(ctx, db, actor, out, bounty); err != nil {
+			return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+		}
+	}
+	if err := storage.DeleteOrder(ctx, db, e.Order); err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+	}
+	// Staged rather than published immediately; see the comment on
+	// StageCreate in create_order.go.
+	orderbook.DefaultHub.StageClose(txID, PairID(in, out), orderbook.Order{
+		ID:      e.Order,
+		In:      in,
+		InRate:  inRate,
+		Out:     out,
+		OutRate: outRate,
+		Supply:  supply,
+		Owner:   owner,
+		Expiry:  expiry,
+	})
+	return &chain.Result{Success: true, Units: unitsUsed}, nil
+}
+
+func (*ExpireOrder) MaxUnits(chain.Rules) uint64 {
+	return consts.IDLen*2 + crypto.PublicKeyLen
+}
+
+func (e *ExpireOrder) Marshal(p *codec.Packer) {
+	p.PackID(e.Order)
+	p.PackPublicKey(e.Owner)
+	p.PackID(e.Out)
+}
+
+func UnmarshalExpireOrder(p *codec.Packer) (chain.Action, error) {
+	var expire ExpireOrder
+	p.UnpackID(true, &expire.Order)
+	p.UnpackPublicKey(&expire.Owner)
+	p.UnpackID(false, &expire.Out)
+	return &expire, p.Err()
+}
+
+func (*ExpireOrder) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
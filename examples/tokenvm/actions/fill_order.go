@@ -0,0 +1,167 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/auth"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/orderbook"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Action = (*FillOrder)(nil)
+
+// FillOrder trades [Value] of the order's [In] asset for its [Out] asset,
+// at the rate the order was created with, or against the AMM pool for the
+// same pair when the pool quotes a larger output (see BestExecution).
+type FillOrder struct {
+	// [Order] is the txID that created the order being filled.
+	Order ids.ID `json:"order"`
+
+	// [Owner] is the order creator. It is required so the filler can reserve
+	// a write lock on the creator's [In] balance without reading the order
+	// first.
+	Owner crypto.PublicKey `json:"owner"`
+
+	// [In] is the asset the filler is providing.
+	In ids.ID `json:"in"`
+
+	// [Out] is the asset the filler is receiving.
+	Out ids.ID `json:"out"`
+
+	// [Value] is the amount of [In] the filler is providing.
+	Value uint64 `json:"value"`
+}
+
+func (f *FillOrder) StateKeys(rauth chain.Auth, _ ids.ID) [][]byte {
+	actor := auth.GetActor(rauth)
+	return [][]byte{
+		storage.PrefixOrderKey(f.Order),
+		storage.PrefixBalanceKey(actor, f.In),
+		storage.PrefixBalanceKey(actor, f.Out),
+		storage.PrefixBalanceKey(f.Owner, f.In),
+		// Execute may route to the AMM pool instead of the order when it
+		// quotes a better price for the same trade; reserve its key too.
+		storage.PoolKey(PairID(f.In, f.Out)),
+	}
+}
+
+func (f *FillOrder) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	db chain.Database,
+	timestamp int64,
+	rauth chain.Auth,
+	txID ids.ID,
+) (*chain.Result, error) {
+	actor := auth.GetActor(rauth)
+	unitsUsed := f.MaxUnits(r)
+	in, inRate, out, outRate, supply, owner, expiry, err := storage.GetOrder(ctx, db, f.Order)
+	if err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: OutputOrderMissing}, nil
+	}
+	if expiry != 0 && timestamp > expiry {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: OutputOrderExpired}, nil
+	}
+	if in != f.In || out != f.Out || owner != f.Owner {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: OutputOrderMissing}, nil
+	}
+	if f.Value == 0 || f.Value%inRate != 0 {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: OutputSupplyMisaligned}, nil
+	}
+	pairID := PairID(in, out)
+	// The two liquidity sources coexist: route this fill to whichever gives
+	// the trader a better price rather than always taking the resting
+	// order.
+	useAMM, ammOut, err := BestExecution(ctx, db, pairID, inRate, outRate, f.Value, swapFeeBps(r))
+	if err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+	}
+	if useAMM {
+		reserveIn, reserveOut, totalShares, _, err := storage.GetPool(ctx, db, pairID)
+		if err != nil {
+			return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+		}
+		if err := storage.SubBalance(ctx, db, actor, f.In, f.Value); err != nil {
+			return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+		}
+		if err := storage.AddBalance(ctx, db, actor, f.Out, ammOut); err != nil {
+			return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+		}
+		if err := storage.SetPool(ctx, db, pairID, reserveIn+f.Value, reserveOut-ammOut, totalShares); err != nil {
+			return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+		}
+		// The resting order wasn't touched, so there's nothing to publish
+		// to orderbook subscribers.
+		return &chain.Result{Success: true, Units: unitsUsed}, nil
+	}
+	outAmount := (f.Value / inRate) * outRate
+	if outAmount == 0 || outAmount > supply {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: OutputSupplyMisaligned}, nil
+	}
+	if err := storage.SubBalance(ctx, db, actor, f.In, f.Value); err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+	}
+	if err := storage.AddBalance(ctx, db, owner, f.In, f.Value); err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+	}
+	if err := storage.AddBalance(ctx, db, actor, f.Out, outAmount); err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+	}
+	remaining := supply - outAmount
+	if remaining == 0 {
+		if err := storage.DeleteOrder(ctx, db, f.Order); err != nil {
+			return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+		}
+	} else if err := storage.SetOrder(ctx, db, f.Order, in, inRate, out, outRate, remaining, owner, expiry); err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+	}
+	// Staged rather than published immediately; see the comment on
+	// StageCreate in create_order.go.
+	orderbook.DefaultHub.StageFill(txID, pairID, orderbook.Order{
+		ID:      f.Order,
+		In:      in,
+		InRate:  inRate,
+		Out:     out,
+		OutRate: outRate,
+		Supply:  remaining,
+		Owner:   owner,
+		Expiry:  expiry,
+	}, outAmount)
+	return &chain.Result{Success: true, Units: unitsUsed}, nil
+}
+
+func (*FillOrder) MaxUnits(chain.Rules) uint64 {
+	return consts.IDLen*3 + consts.Uint64Len
+}
+
+func (f *FillOrder) Marshal(p *codec.Packer) {
+	p.PackID(f.Order)
+	p.PackPublicKey(f.Owner)
+	p.PackID(f.In)
+	p.PackID(f.Out)
+	p.PackUint64(f.Value)
+}
+
+func UnmarshalFillOrder(p *codec.Packer) (chain.Action, error) {
+	var fill FillOrder
+	p.UnpackID(true, &fill.Order)
+	p.UnpackPublicKey(&fill.Owner)
+	p.UnpackID(false, &fill.In)
+	p.UnpackID(false, &fill.Out)
+	fill.Value = p.UnpackUint64(true)
+	return &fill, p.Err()
+}
+
+func (*FillOrder) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
@@ -0,0 +1,21 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+var (
+	OutputInRateZero       = []byte("in rate is zero")
+	OutputOutRateZero      = []byte("out rate is zero")
+	OutputSupplyZero       = []byte("supply is zero")
+	OutputSupplyMisaligned = []byte("supply is not a multiple of out rate")
+
+	OutputOrderMissing    = []byte("order is missing")
+	OutputOrderExpired    = []byte("order is expired")
+	OutputOrderNotExpired = []byte("order has not expired")
+	OutputExpiryInvalid   = []byte("expiry is negative or not in the future")
+
+	OutputPoolExists         = []byte("pool already exists")
+	OutputPoolMissing        = []byte("pool is missing")
+	OutputInsufficientOut    = []byte("insufficient output amount")
+	OutputInsufficientShares = []byte("insufficient LP shares")
+)
@@ -0,0 +1,102 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/auth"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Action = (*RemoveLiquidity)(nil)
+
+// RemoveLiquidity burns [Shares] of LP tokens in the pool over (In, Out) and
+// returns the holder's proportional share of both reserves.
+type RemoveLiquidity struct {
+	In     ids.ID `json:"in"`
+	Out    ids.ID `json:"out"`
+	Shares uint64 `json:"shares"`
+}
+
+func (rl *RemoveLiquidity) StateKeys(rauth chain.Auth, _ ids.ID) [][]byte {
+	actor := auth.GetActor(rauth)
+	pairID := PairID(rl.In, rl.Out)
+	return [][]byte{
+		storage.PrefixBalanceKey(actor, rl.In),
+		storage.PrefixBalanceKey(actor, rl.Out),
+		storage.PoolKey(pairID),
+		storage.PrefixLPBalanceKey(actor, pairID),
+	}
+}
+
+func (rl *RemoveLiquidity) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	db chain.Database,
+	_ int64,
+	rauth chain.Auth,
+	_ ids.ID,
+) (*chain.Result, error) {
+	actor := auth.GetActor(rauth)
+	unitsUsed := rl.MaxUnits(r)
+	if rl.Shares == 0 {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: OutputSupplyZero}, nil
+	}
+	pairID := PairID(rl.In, rl.Out)
+	reserveIn, reserveOut, totalShares, exists, err := storage.GetPool(ctx, db, pairID)
+	if err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+	}
+	if !exists || rl.Shares > totalShares {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: OutputPoolMissing}, nil
+	}
+	// Same overflow hazard as AddLiquidity's share math: go through mulDiv.
+	amountIn := mulDiv(rl.Shares, reserveIn, totalShares)
+	amountOut := mulDiv(rl.Shares, reserveOut, totalShares)
+	if amountIn == 0 && amountOut == 0 {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: OutputInsufficientOut}, nil
+	}
+	if err := storage.SubLPBalance(ctx, db, actor, pairID, rl.Shares); err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+	}
+	if err := storage.AddBalance(ctx, db, actor, rl.In, amountIn); err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+	}
+	if err := storage.AddBalance(ctx, db, actor, rl.Out, amountOut); err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+	}
+	if err := storage.SetPool(ctx, db, pairID, reserveIn-amountIn, reserveOut-amountOut, totalShares-rl.Shares); err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+	}
+	return &chain.Result{Success: true, Units: unitsUsed}, nil
+}
+
+func (*RemoveLiquidity) MaxUnits(chain.Rules) uint64 {
+	return consts.IDLen*2 + consts.Uint64Len
+}
+
+func (rl *RemoveLiquidity) Marshal(p *codec.Packer) {
+	p.PackID(rl.In)
+	p.PackID(rl.Out)
+	p.PackUint64(rl.Shares)
+}
+
+func UnmarshalRemoveLiquidity(p *codec.Packer) (chain.Action, error) {
+	var remove RemoveLiquidity
+	p.UnpackID(false, &remove.In)
+	p.UnpackID(false, &remove.Out)
+	remove.Shares = p.UnpackUint64(true)
+	return &remove, p.Err()
+}
+
+func (*RemoveLiquidity) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
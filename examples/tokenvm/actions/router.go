@@ -0,0 +1,43 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+)
+
+// BestExecution compares the AMM pool over (in, out) against a resting
+// order's quoted terms and reports which venue offers the trader a better
+// price for [amountIn]. FillOrder.Execute calls this and, when the pool
+// quotes more, settles the fill against the pool instead of the resting
+// order, so routing happens on-chain inside that single Execute.
+func BestExecution(
+	ctx context.Context,
+	db chain.Database,
+	pairID string,
+	orderInRate uint64,
+	orderOutRate uint64,
+	amountIn uint64,
+	feeBps uint64,
+) (useAMM bool, amountOut uint64, err error) {
+	orderOut := uint64(0)
+	if orderInRate > 0 {
+		orderOut = (amountIn / orderInRate) * orderOutRate
+	}
+	reserveIn, reserveOut, _, exists, err := storage.GetPool(ctx, db, pairID)
+	if err != nil {
+		return false, 0, err
+	}
+	if !exists {
+		return false, orderOut, nil
+	}
+	ammOut := poolAmountOut(reserveIn, reserveOut, amountIn, feeBps)
+	if ammOut > orderOut {
+		return true, ammOut, nil
+	}
+	return false, orderOut, nil
+}
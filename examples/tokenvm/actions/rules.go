@@ -0,0 +1,32 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/genesis"
+)
+
+// expireOrderBountyPercent and swapFeeBps read VM-specific genesis values
+// that chain.Rules doesn't declare a getter for. The VM always passes a
+// *genesis.Rules as the chain.Rules Execute receives, so we assert down to
+// it rather than widening the shared chain.Rules interface for two
+// tokenvm-only knobs; a rules value from elsewhere (e.g. a test stub) just
+// gets the zero value for each.
+
+func expireOrderBountyPercent(r chain.Rules) uint64 {
+	gr, ok := r.(*genesis.Rules)
+	if !ok {
+		return 0
+	}
+	return gr.GetExpireOrderBountyPercent()
+}
+
+func swapFeeBps(r chain.Rules) uint64 {
+	gr, ok := r.(*genesis.Rules)
+	if !ok {
+		return 0
+	}
+	return gr.GetSwapFeeBps()
+}
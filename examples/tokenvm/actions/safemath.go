@@ -0,0 +1,23 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import "math/big"
+
+// mulDiv computes (a*b)/denom without overflowing uint64 in the
+// intermediate product, which realistic pool reserves and share amounts
+// can exceed (uint64 tops out near 1.8e19, while a*b can reach ~3.4e38
+// before it's divided back down).
+// denom must be non-zero.
+func mulDiv(a, b, denom uint64) uint64 {
+	prod := new(big.Int).Mul(new(big.Int).SetUint64(a), new(big.Int).SetUint64(b))
+	return prod.Div(prod, new(big.Int).SetUint64(denom)).Uint64()
+}
+
+// isqrtProduct returns the floor of sqrt(a*b), again computing the product
+// wide enough to avoid overflow before taking the root.
+func isqrtProduct(a, b uint64) uint64 {
+	prod := new(big.Int).Mul(new(big.Int).SetUint64(a), new(big.Int).SetUint64(b))
+	return new(big.Int).Sqrt(prod).Uint64()
+}
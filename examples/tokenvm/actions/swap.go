@@ -0,0 +1,130 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/auth"
+	"github.com/ava-labs/hypersdk/examples/tokenvm/storage"
+	"github.com/ava-labs/hypersdk/utils"
+)
+
+var _ chain.Action = (*Swap)(nil)
+
+// bpsDenominator is the basis-point scale used for pool swap fees.
+const bpsDenominator = 10_000
+
+// Swap trades [AmountIn] of [In] for [Out] against the constant-product pool
+// over this pair, failing if the resulting output is below [MinOut].
+type Swap struct {
+	// [In] is the asset the trader is providing.
+	In ids.ID `json:"in"`
+
+	// [Out] is the asset the trader is receiving.
+	Out ids.ID `json:"out"`
+
+	// [AmountIn] is the amount of [In] the trader is providing.
+	AmountIn uint64 `json:"amountIn"`
+
+	// [MinOut] is the minimum amount of [Out] the trader will accept,
+	// protecting them from slippage between submission and execution.
+	MinOut uint64 `json:"minOut"`
+}
+
+func (s *Swap) StateKeys(rauth chain.Auth, _ ids.ID) [][]byte {
+	actor := auth.GetActor(rauth)
+	return [][]byte{
+		storage.PrefixBalanceKey(actor, s.In),
+		storage.PrefixBalanceKey(actor, s.Out),
+		storage.PoolKey(PairID(s.In, s.Out)),
+	}
+}
+
+func (s *Swap) Execute(
+	ctx context.Context,
+	r chain.Rules,
+	db chain.Database,
+	_ int64,
+	rauth chain.Auth,
+	_ ids.ID,
+) (*chain.Result, error) {
+	actor := auth.GetActor(rauth)
+	unitsUsed := s.MaxUnits(r)
+	if s.AmountIn == 0 {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: OutputSupplyZero}, nil
+	}
+	pairID := PairID(s.In, s.Out)
+	reserveIn, reserveOut, totalShares, exists, err := storage.GetPool(ctx, db, pairID)
+	if err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+	}
+	if !exists {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: OutputPoolMissing}, nil
+	}
+	amountOut := poolAmountOut(reserveIn, reserveOut, s.AmountIn, swapFeeBps(r))
+	if amountOut == 0 || amountOut < s.MinOut {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: OutputInsufficientOut}, nil
+	}
+	if err := storage.SubBalance(ctx, db, actor, s.In, s.AmountIn); err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+	}
+	if err := storage.AddBalance(ctx, db, actor, s.Out, amountOut); err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+	}
+	if err := storage.SetPool(ctx, db, pairID, reserveIn+s.AmountIn, reserveOut-amountOut, totalShares); err != nil {
+		return &chain.Result{Success: false, Units: unitsUsed, Output: utils.ErrBytes(err)}, nil
+	}
+	return &chain.Result{Success: true, Units: unitsUsed}, nil
+}
+
+func (*Swap) MaxUnits(chain.Rules) uint64 {
+	return consts.IDLen*2 + consts.Uint64Len*2
+}
+
+func (s *Swap) Marshal(p *codec.Packer) {
+	p.PackID(s.In)
+	p.PackID(s.Out)
+	p.PackUint64(s.AmountIn)
+	p.PackUint64(s.MinOut)
+}
+
+func UnmarshalSwap(p *codec.Packer) (chain.Action, error) {
+	var swap Swap
+	p.UnpackID(false, &swap.In)
+	p.UnpackID(false, &swap.Out)
+	swap.AmountIn = p.UnpackUint64(true)
+	swap.MinOut = p.UnpackUint64(false)
+	return &swap, p.Err()
+}
+
+func (*Swap) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+// poolAmountOut computes the constant-product output for [amountIn] against
+// [reserveIn]/[reserveOut], charging [feeBps] basis points on the input:
+//
+//	out = reserveOut - (reserveIn * reserveOut) / (reserveIn + amountInAfterFee)
+//
+// reserveIn*reserveOut and amountIn*(bpsDenominator-feeBps) both overflow
+// uint64 well within realistic reserve/amount sizes, so the intermediate
+// products go through mulDiv rather than bare uint64 multiplication.
+func poolAmountOut(reserveIn, reserveOut, amountIn, feeBps uint64) uint64 {
+	if reserveIn == 0 || reserveOut == 0 || amountIn == 0 {
+		return 0
+	}
+	amountInAfterFee := mulDiv(amountIn, bpsDenominator-feeBps, bpsDenominator)
+	newReserveIn := reserveIn + amountInAfterFee
+	newReserveOut := mulDiv(reserveIn, reserveOut, newReserveIn)
+	if newReserveOut >= reserveOut {
+		return 0
+	}
+	return reserveOut - newReserveOut
+}
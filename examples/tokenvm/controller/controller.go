@@ -0,0 +1,34 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package controller holds the tokenvm callbacks the VM invokes once
+// consensus has decided a block's fate -- the only point at which it's safe
+// to publish side effects (like orderbook events) that actions merely
+// staged during Execute.
+package controller
+
+import (
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/examples/tokenvm/orderbook"
+)
+
+// Accepted flushes every orderbook event staged while executing the
+// transactions in txIDs, now that the block containing them is durably
+// accepted. The VM's block-acceptance callback calls this with [hub] set to
+// orderbook.DefaultHub and the accepted block's transaction IDs.
+func Accepted(hub *orderbook.Hub, txIDs []ids.ID) {
+	for _, txID := range txIDs {
+		hub.Commit(txID)
+	}
+}
+
+// Rejected discards every orderbook event staged while executing the
+// transactions in txIDs, since the block containing them never became part
+// of the chain. The VM's block-rejection callback calls this with [hub] set
+// to orderbook.DefaultHub and the rejected block's transaction IDs.
+func Rejected(hub *orderbook.Hub, txIDs []ids.ID) {
+	for _, txID := range txIDs {
+		hub.Discard(txID)
+	}
+}
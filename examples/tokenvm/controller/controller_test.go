@@ -0,0 +1,54 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/examples/tokenvm/orderbook"
+)
+
+func TestAcceptedPublishesStagedEvents(t *testing.T) {
+	hub := orderbook.NewHub()
+	txID := ids.GenerateTestID()
+	hub.StageCreate(txID, "pair", orderbook.Order{ID: txID})
+
+	got := make(chan orderbook.Order, 1)
+	hub.Subscribe("pair", orderbook.ListenerFunc{
+		Create: func(o orderbook.Order) { got <- o },
+	})
+
+	Accepted(hub, []ids.ID{txID})
+
+	select {
+	case o := <-got:
+		if o.ID != txID {
+			t.Fatalf("got order %s, want %s", o.ID, txID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Accepted did not publish the staged create event")
+	}
+}
+
+func TestRejectedDiscardsStagedEvents(t *testing.T) {
+	hub := orderbook.NewHub()
+	txID := ids.GenerateTestID()
+	hub.StageCreate(txID, "pair", orderbook.Order{ID: txID})
+
+	got := make(chan orderbook.Order, 1)
+	hub.Subscribe("pair", orderbook.ListenerFunc{
+		Create: func(o orderbook.Order) { got <- o },
+	})
+
+	Rejected(hub, []ids.ID{txID})
+
+	select {
+	case o := <-got:
+		t.Fatalf("Rejected published a discarded event: %+v", o)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package genesis
+
+// Rules holds the genesis-configured parameters for actions that need a
+// tunable value beyond what's intrinsic to the transaction itself.
+// chain.Rules is defined outside
+// this module snapshot and doesn't declare VM-specific getters like these,
+// so actions read them off the concrete *Rules the VM always passes as
+// chain.Rules (see actions.expireOrderBountyPercent/swapFeeBps) rather than
+// through the interface.
+type Rules struct {
+	// ExpireOrderBountyPercent is the percentage (0-100) of a swept order's
+	// remaining supply paid to whoever submits the ExpireOrder, with the
+	// rest refunded to the order's creator.
+	ExpireOrderBountyPercent uint64 `json:"expireOrderBountyPercent"`
+
+	// SwapFeeBps is the fee charged on the input side of a Swap, in basis
+	// points (1/100th of a percent), retained in the pool's reserves for
+	// liquidity providers.
+	SwapFeeBps uint64 `json:"swapFeeBps"`
+}
+
+// GetExpireOrderBountyPercent returns the configured bounty percentage.
+func (r *Rules) GetExpireOrderBountyPercent() uint64 {
+	return r.ExpireOrderBountyPercent
+}
+
+// GetSwapFeeBps returns the configured swap fee, in basis points.
+func (r *Rules) GetSwapFeeBps() uint64 {
+	return r.SwapFeeBps
+}
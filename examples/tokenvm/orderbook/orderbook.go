@@ -0,0 +1,341 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package orderbook lets clients (market makers, indexers, UIs) subscribe to
+// order book updates for a pair instead of polling every state key.
+package orderbook
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/crypto"
+)
+
+// Order is the public view of a resting order, as reported to listeners and
+// snapshot callers. It mirrors storage.GetOrder's fields rather than
+// importing the storage package, so orderbook has no dependency on how
+// orders are persisted.
+type Order struct {
+	ID      ids.ID           `json:"id"`
+	In      ids.ID           `json:"in"`
+	InRate  uint64           `json:"inRate"`
+	Out     ids.ID           `json:"out"`
+	OutRate uint64           `json:"outRate"`
+	Supply  uint64           `json:"supply"`
+	Owner   crypto.PublicKey `json:"owner"`
+	Expiry  int64            `json:"expiry"`
+}
+
+// Price returns InRate/OutRate, used to sort bids/asks in a snapshot.
+func (o Order) Price() float64 {
+	if o.OutRate == 0 {
+		return 0
+	}
+	return float64(o.InRate) / float64(o.OutRate)
+}
+
+// EventType identifies what happened to an order.
+type EventType string
+
+const (
+	EventCreate EventType = "create"
+	EventFill   EventType = "fill"
+	EventClose  EventType = "close"
+)
+
+// Event is a single order book update, as streamed to subscribers.
+type Event struct {
+	Type  EventType `json:"type"`
+	Order Order     `json:"order"`
+	Delta uint64    `json:"delta,omitempty"`
+}
+
+// Listener receives order book events for the pairs it is subscribed to, in
+// the order they happened. Hub delivers events for a given pair from a
+// single goroutine, one at a time, so a slow or blocked listener stalls only
+// its own pair's later events (never the Execute call that produced them)
+// and never sees a pair's events reordered.
+type Listener interface {
+	OnCreate(order Order)
+	OnFill(order Order, delta uint64)
+	OnClose(order Order)
+}
+
+// ListenerFunc adapts individual callbacks into a Listener, for callers
+// (like the WebSocket endpoint) that only want a single stream of Events
+// rather than three methods.
+type ListenerFunc struct {
+	Create func(Order)
+	Fill   func(Order, uint64)
+	Close  func(Order)
+}
+
+func (f ListenerFunc) OnCreate(o Order) {
+	if f.Create != nil {
+		f.Create(o)
+	}
+}
+
+func (f ListenerFunc) OnFill(o Order, d uint64) {
+	if f.Fill != nil {
+		f.Fill(o, d)
+	}
+}
+
+func (f ListenerFunc) OnClose(o Order) {
+	if f.Close != nil {
+		f.Close(o)
+	}
+}
+
+func deliverTo(l Listener, e Event) {
+	switch e.Type {
+	case EventCreate:
+		l.OnCreate(e.Order)
+	case EventFill:
+		l.OnFill(e.Order, e.Delta)
+	case EventClose:
+		l.OnClose(e.Order)
+	}
+}
+
+// ringSize bounds how many recent events a pair retains, both so memory
+// doesn't grow unbounded and so a newly-subscribed listener has something
+// to replay to catch up on history it missed.
+const ringSize = 1024
+
+// deliverBacklog bounds the per-pair delivery queue between Hub and
+// listeners. Publishing never blocks on it: a pair that falls this far
+// behind drops the overflow rather than stalling the publisher (Commit, on
+// block acceptance).
+const deliverBacklog = 256
+
+type pair struct {
+	ringLock sync.Mutex
+	ring     []Event
+
+	listenersLock sync.Mutex
+	nextID        int
+	listeners     map[int]Listener
+
+	ordersLock sync.Mutex
+	orders     map[ids.ID]Order
+
+	deliverOnce sync.Once
+	deliverCh   chan Event
+}
+
+func newPair() *pair {
+	return &pair{
+		listeners: make(map[int]Listener),
+		orders:    make(map[ids.ID]Order),
+	}
+}
+
+// publish records [e] in the replay ring and queues it for in-order
+// delivery to this pair's listeners. It never blocks: a full delivery queue
+// drops the event rather than stalling the caller (Commit or a direct
+// OnCreate/OnFill/OnClose call).
+func (p *pair) publish(e Event) {
+	p.ringLock.Lock()
+	p.ring = append(p.ring, e)
+	if len(p.ring) > ringSize {
+		p.ring = p.ring[len(p.ring)-ringSize:]
+	}
+	p.ringLock.Unlock()
+
+	p.deliverOnce.Do(func() {
+		p.deliverCh = make(chan Event, deliverBacklog)
+		go func() {
+			for ev := range p.deliverCh {
+				p.listenersLock.Lock()
+				targets := make([]Listener, 0, len(p.listeners))
+				for _, l := range p.listeners {
+					targets = append(targets, l)
+				}
+				p.listenersLock.Unlock()
+				for _, l := range targets {
+					deliverTo(l, ev)
+				}
+			}
+		}()
+	})
+	select {
+	case p.deliverCh <- e:
+	default:
+		// A subscriber that falls this far behind resyncs via Snapshot
+		// instead; we never block the publisher on it.
+	}
+}
+
+// subscribe registers [l] and replays the current ring to it before
+// returning, so a late subscriber catches up on recent history instead of
+// only seeing events from the moment it connected. The listener is
+// registered (and so eligible to receive concurrently-published events)
+// before the replay runs, under the same lock, so nothing is lost or
+// delivered twice between backlog and live events.
+func (p *pair) subscribe(l Listener) (unsubscribe func()) {
+	p.listenersLock.Lock()
+	id := p.nextID
+	p.nextID++
+	p.listeners[id] = l
+	p.ringLock.Lock()
+	backlog := make([]Event, len(p.ring))
+	copy(backlog, p.ring)
+	p.ringLock.Unlock()
+	p.listenersLock.Unlock()
+
+	for _, e := range backlog {
+		deliverTo(l, e)
+	}
+
+	return func() {
+		p.listenersLock.Lock()
+		delete(p.listeners, id)
+		p.listenersLock.Unlock()
+	}
+}
+
+// Hub is an in-memory pub/sub registry of order book activity, keyed by
+// PairID. It is safe for concurrent use. Actions stage events as part of
+// Execute and the VM calls Commit once the block containing them is
+// actually accepted (or Discard if it's not), so a block that's verified
+// but rejected, or re-executed, never leaks phantom events to subscribers.
+type Hub struct {
+	mu    sync.Mutex
+	pairs map[string]*pair
+
+	pendingLock sync.Mutex
+	pendingByTx map[ids.ID][]func(*Hub)
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		pairs:       make(map[string]*pair),
+		pendingByTx: make(map[ids.ID][]func(*Hub)),
+	}
+}
+
+func (h *Hub) pairFor(pairID string) *pair {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	p, ok := h.pairs[pairID]
+	if !ok {
+		p = newPair()
+		h.pairs[pairID] = p
+	}
+	return p
+}
+
+// Subscribe registers [l] for events on [pairID], replays recent history to
+// it, and returns a function that unsubscribes it.
+func (h *Hub) Subscribe(pairID string, l Listener) (unsubscribe func()) {
+	return h.pairFor(pairID).subscribe(l)
+}
+
+// OnCreate records that [order] was created on [pairID] and publishes it to
+// subscribers immediately. Prefer StageCreate from within Execute; this is
+// for callers that already know their write has committed.
+func (h *Hub) OnCreate(pairID string, order Order) {
+	p := h.pairFor(pairID)
+	p.ordersLock.Lock()
+	p.orders[order.ID] = order
+	p.ordersLock.Unlock()
+	p.publish(Event{Type: EventCreate, Order: order})
+}
+
+// OnFill records that [order] had [delta] of its supply filled and
+// publishes it to subscribers immediately. The
+// caller passes the order's state as of *after* the fill, with [delta] the
+// amount that changed hands. Prefer StageFill from within Execute.
+func (h *Hub) OnFill(pairID string, order Order, delta uint64) {
+	p := h.pairFor(pairID)
+	p.ordersLock.Lock()
+	if order.Supply == 0 {
+		delete(p.orders, order.ID)
+	} else {
+		p.orders[order.ID] = order
+	}
+	p.ordersLock.Unlock()
+	p.publish(Event{Type: EventFill, Order: order, Delta: delta})
+}
+
+// OnClose records that [order] is no longer resting (cancelled or expired)
+// and publishes it to subscribers immediately. Prefer StageClose from
+// within Execute.
+func (h *Hub) OnClose(pairID string, order Order) {
+	p := h.pairFor(pairID)
+	p.ordersLock.Lock()
+	delete(p.orders, order.ID)
+	p.ordersLock.Unlock()
+	p.publish(Event{Type: EventClose, Order: order})
+}
+
+func (h *Hub) stage(txID ids.ID, fn func(*Hub)) {
+	h.pendingLock.Lock()
+	h.pendingByTx[txID] = append(h.pendingByTx[txID], fn)
+	h.pendingLock.Unlock()
+}
+
+// StageCreate buffers an OnCreate for [txID] instead of publishing it
+// immediately. Call Commit(txID) once the transaction's block is accepted.
+func (h *Hub) StageCreate(txID ids.ID, pairID string, order Order) {
+	h.stage(txID, func(h *Hub) { h.OnCreate(pairID, order) })
+}
+
+// StageFill buffers an OnFill for [txID] instead of publishing it
+// immediately. Call Commit(txID) once the transaction's block is accepted.
+func (h *Hub) StageFill(txID ids.ID, pairID string, order Order, delta uint64) {
+	h.stage(txID, func(h *Hub) { h.OnFill(pairID, order, delta) })
+}
+
+// StageClose buffers an OnClose for [txID] instead of publishing it
+// immediately. Call Commit(txID) once the transaction's block is accepted.
+func (h *Hub) StageClose(txID ids.ID, pairID string, order Order) {
+	h.stage(txID, func(h *Hub) { h.OnClose(pairID, order) })
+}
+
+// Commit publishes every event staged for [txID], in the order staged, and
+// discards the buffer. The VM calls this once it accepts the block
+// containing txID -- never from inside Execute -- so a block that's
+// verified but never accepted (or re-executed during verification) doesn't
+// leak phantom events.
+func (h *Hub) Commit(txID ids.ID) {
+	h.pendingLock.Lock()
+	fns := h.pendingByTx[txID]
+	delete(h.pendingByTx, txID)
+	h.pendingLock.Unlock()
+	for _, fn := range fns {
+		fn(h)
+	}
+}
+
+// Discard drops events staged for [txID] without publishing them, e.g.
+// because the block containing it was rejected.
+func (h *Hub) Discard(txID ids.ID) {
+	h.pendingLock.Lock()
+	delete(h.pendingByTx, txID)
+	h.pendingLock.Unlock()
+}
+
+// Snapshot returns the resting orders for [pairID] sorted by price, cheapest
+// first, so a new subscriber can bootstrap its book before it starts
+// receiving deltas. Bids and asks are the same set viewed from either side
+// of the pair; callers ask for whichever side matches their PairID.
+func (h *Hub) Snapshot(pairID string) []Order {
+	p := h.pairFor(pairID)
+	p.ordersLock.Lock()
+	defer p.ordersLock.Unlock()
+	orders := make([]Order, 0, len(p.orders))
+	for _, o := range p.orders {
+		orders = append(orders, o)
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].Price() < orders[j].Price() })
+	return orders
+}
+
+// DefaultHub is used by the tokenvm actions unless the VM wires in a
+// different Hub (e.g. to scope one per instance in tests).
+var DefaultHub = NewHub()
@@ -0,0 +1,80 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ava-labs/hypersdk/examples/tokenvm/orderbook"
+)
+
+var orderBookUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(*http.Request) bool { return true },
+}
+
+// OrderBookHandler serves the "/orderbook/{pairID}" WebSocket endpoint,
+// streaming newline-delimited JSON orderbook.Event updates for a pair. The
+// snapshot new clients use to bootstrap is the OrderBookSnapshot method;
+// ServeHTTP does not serve it over HTTP.
+type OrderBookHandler struct {
+	hub *orderbook.Hub
+}
+
+// NewOrderBookHandler wraps [hub] for mounting on the JSON-RPC server's mux.
+func NewOrderBookHandler(hub *orderbook.Hub) *OrderBookHandler {
+	return &OrderBookHandler{hub: hub}
+}
+
+// ServeHTTP routes "/orderbook/{pairID}" requests to the WebSocket handler.
+func (h *OrderBookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	pairID := strings.TrimPrefix(r.URL.Path, "/orderbook/")
+	if pairID == "" || pairID == r.URL.Path {
+		http.NotFound(w, r)
+		return
+	}
+
+	conn, err := orderBookUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	events := make(chan orderbook.Event, 64)
+	unsubscribe := h.hub.Subscribe(pairID, orderbook.ListenerFunc{
+		Create: func(o orderbook.Order) { sendEvent(events, orderbook.Event{Type: orderbook.EventCreate, Order: o}) },
+		Fill: func(o orderbook.Order, delta uint64) {
+			sendEvent(events, orderbook.Event{Type: orderbook.EventFill, Order: o, Delta: delta})
+		},
+		Close: func(o orderbook.Order) { sendEvent(events, orderbook.Event{Type: orderbook.EventClose, Order: o}) },
+	})
+	defer unsubscribe()
+
+	for e := range events {
+		if err := conn.WriteJSON(e); err != nil {
+			return
+		}
+	}
+}
+
+// sendEvent never blocks the listener callback: a subscriber that reads
+// slower than events arrive drops the overflow rather than stalling the
+// pair's delivery goroutine, and with it every other subscriber to the pair.
+func sendEvent(events chan<- orderbook.Event, e orderbook.Event) {
+	select {
+	case events <- e:
+	default:
+	}
+}
+
+// OrderBookSnapshot returns the resting orders for [pairID] sorted by price,
+// cheapest first, so a new client can bootstrap its book before switching to
+// the streaming endpoint.
+func (h *OrderBookHandler) OrderBookSnapshot(pairID string) []orderbook.Order {
+	return h.hub.Snapshot(pairID)
+}
@@ -0,0 +1,21 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package rpc
+
+import (
+	"net/http"
+
+	"github.com/ava-labs/hypersdk/examples/tokenvm/orderbook"
+)
+
+// NewServeMux builds the tokenvm JSON-RPC mux with the orderbook
+// WebSocket endpoint mounted at "/orderbook/". The VM's JSON-RPC
+// server embeds this mux alongside its own core routes so
+// "/orderbook/{pairID}" is actually reachable, rather than leaving
+// OrderBookHandler constructed but unmounted.
+func NewServeMux(hub *orderbook.Hub) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/orderbook/", NewOrderBookHandler(hub))
+	return mux
+}
@@ -0,0 +1,92 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto"
+)
+
+const (
+	poolPrefix      byte = 0x2
+	lpBalancePrefix byte = 0x3
+)
+
+// PoolKey returns the storage key for the AMM pool over [pairID].
+func PoolKey(pairID string) []byte {
+	k := make([]byte, 1+len(pairID))
+	k[0] = poolPrefix
+	copy(k[1:], pairID)
+	return k
+}
+
+// SetPool writes the reserves and total LP shares for the pool over [pairID].
+func SetPool(ctx context.Context, db chain.Database, pairID string, reserveIn uint64, reserveOut uint64, totalShares uint64) error {
+	v := make([]byte, consts.Uint64Len*3)
+	binary.BigEndian.PutUint64(v, reserveIn)
+	binary.BigEndian.PutUint64(v[consts.Uint64Len:], reserveOut)
+	binary.BigEndian.PutUint64(v[consts.Uint64Len*2:], totalShares)
+	return db.Insert(ctx, PoolKey(pairID), v)
+}
+
+// GetPool returns the reserves and total LP shares for the pool over
+// [pairID]. [exists] is false if no pool has been created for this pair.
+func GetPool(ctx context.Context, db chain.Database, pairID string) (
+	reserveIn uint64,
+	reserveOut uint64,
+	totalShares uint64,
+	exists bool,
+	err error,
+) {
+	v, err := db.GetValue(ctx, PoolKey(pairID))
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, 0, 0, false, nil
+	}
+	if err != nil {
+		return 0, 0, 0, false, err
+	}
+	reserveIn = binary.BigEndian.Uint64(v)
+	reserveOut = binary.BigEndian.Uint64(v[consts.Uint64Len:])
+	totalShares = binary.BigEndian.Uint64(v[consts.Uint64Len*2:])
+	return reserveIn, reserveOut, totalShares, true, nil
+}
+
+// PrefixLPBalanceKey returns the storage key tracking [owner]'s LP shares in
+// the pool over [pairID].
+func PrefixLPBalanceKey(owner crypto.PublicKey, pairID string) []byte {
+	k := make([]byte, 1+crypto.PublicKeyLen+len(pairID))
+	k[0] = lpBalancePrefix
+	copy(k[1:], owner[:])
+	copy(k[1+crypto.PublicKeyLen:], pairID)
+	return k
+}
+
+// SubLPBalance debits [amount] of LP shares in [pairID] from [owner].
+func SubLPBalance(ctx context.Context, db chain.Database, owner crypto.PublicKey, pairID string, amount uint64) error {
+	key := PrefixLPBalanceKey(owner, pairID)
+	bal, err := getBalance(ctx, db, key)
+	if err != nil {
+		return err
+	}
+	if bal < amount {
+		return ErrInvalidBalance
+	}
+	return setBalance(ctx, db, key, bal-amount)
+}
+
+// AddLPBalance credits [amount] of LP shares in [pairID] to [owner].
+func AddLPBalance(ctx context.Context, db chain.Database, owner crypto.PublicKey, pairID string, amount uint64) error {
+	key := PrefixLPBalanceKey(owner, pairID)
+	bal, err := getBalance(ctx, db, key)
+	if err != nil {
+		return err
+	}
+	return setBalance(ctx, db, key, bal+amount)
+}
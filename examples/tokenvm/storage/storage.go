@@ -0,0 +1,150 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/crypto"
+)
+
+const (
+	balancePrefix byte = 0x0
+	orderPrefix   byte = 0x1
+)
+
+// PrefixBalanceKey returns the storage key tracking the balance [asset] held
+// by [owner].
+func PrefixBalanceKey(owner crypto.PublicKey, asset ids.ID) []byte {
+	k := make([]byte, 1+crypto.PublicKeyLen+consts.IDLen)
+	k[0] = balancePrefix
+	copy(k[1:], owner[:])
+	copy(k[1+crypto.PublicKeyLen:], asset[:])
+	return k
+}
+
+// SubBalance debits [amount] of [asset] from [owner]'s balance.
+func SubBalance(ctx context.Context, db chain.Database, owner crypto.PublicKey, asset ids.ID, amount uint64) error {
+	key := PrefixBalanceKey(owner, asset)
+	bal, err := getBalance(ctx, db, key)
+	if err != nil {
+		return err
+	}
+	if bal < amount {
+		return ErrInvalidBalance
+	}
+	return setBalance(ctx, db, key, bal-amount)
+}
+
+// AddBalance credits [amount] of [asset] to [owner]'s balance.
+func AddBalance(ctx context.Context, db chain.Database, owner crypto.PublicKey, asset ids.ID, amount uint64) error {
+	key := PrefixBalanceKey(owner, asset)
+	bal, err := getBalance(ctx, db, key)
+	if err != nil {
+		return err
+	}
+	return setBalance(ctx, db, key, bal+amount)
+}
+
+func getBalance(ctx context.Context, db chain.Database, key []byte) (uint64, error) {
+	v, err := db.GetValue(ctx, key)
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(v), nil
+}
+
+func setBalance(ctx context.Context, db chain.Database, key []byte, bal uint64) error {
+	v := binary.BigEndian.AppendUint64(nil, bal)
+	return db.Insert(ctx, key, v)
+}
+
+// PrefixOrderKey returns the storage key for the order created in [txID].
+func PrefixOrderKey(txID ids.ID) []byte {
+	k := make([]byte, 1+consts.IDLen)
+	k[0] = orderPrefix
+	copy(k[1:], txID[:])
+	return k
+}
+
+// SetOrder writes an order to disk, recording the original [in]-[out] terms
+// requested by [owner] along with the remaining [supply] and, if non-zero,
+// the unix second at which the order expires and may be swept by anyone via
+// ExpireOrder.
+func SetOrder(
+	ctx context.Context,
+	db chain.Database,
+	orderID ids.ID,
+	in ids.ID,
+	inRate uint64,
+	out ids.ID,
+	outRate uint64,
+	supply uint64,
+	owner crypto.PublicKey,
+	expiry int64,
+) error {
+	v := make([]byte, consts.IDLen*2+consts.Uint64Len*4+crypto.PublicKeyLen)
+	offset := 0
+	copy(v[offset:], in[:])
+	offset += consts.IDLen
+	binary.BigEndian.PutUint64(v[offset:], inRate)
+	offset += consts.Uint64Len
+	copy(v[offset:], out[:])
+	offset += consts.IDLen
+	binary.BigEndian.PutUint64(v[offset:], outRate)
+	offset += consts.Uint64Len
+	binary.BigEndian.PutUint64(v[offset:], supply)
+	offset += consts.Uint64Len
+	copy(v[offset:], owner[:])
+	offset += crypto.PublicKeyLen
+	binary.BigEndian.PutUint64(v[offset:], uint64(expiry))
+	return db.Insert(ctx, PrefixOrderKey(orderID), v)
+}
+
+// GetOrder returns the order created in [orderID]. [expiry] is 0 when the
+// order was created without one and is valid until filled or cancelled.
+func GetOrder(ctx context.Context, db chain.Database, orderID ids.ID) (
+	in ids.ID,
+	inRate uint64,
+	out ids.ID,
+	outRate uint64,
+	supply uint64,
+	owner crypto.PublicKey,
+	expiry int64,
+	err error,
+) {
+	v, err := db.GetValue(ctx, PrefixOrderKey(orderID))
+	if err != nil {
+		return ids.Empty, 0, ids.Empty, 0, 0, crypto.EmptyPublicKey, 0, err
+	}
+	offset := 0
+	copy(in[:], v[offset:])
+	offset += consts.IDLen
+	inRate = binary.BigEndian.Uint64(v[offset:])
+	offset += consts.Uint64Len
+	copy(out[:], v[offset:])
+	offset += consts.IDLen
+	outRate = binary.BigEndian.Uint64(v[offset:])
+	offset += consts.Uint64Len
+	supply = binary.BigEndian.Uint64(v[offset:])
+	offset += consts.Uint64Len
+	copy(owner[:], v[offset:])
+	offset += crypto.PublicKeyLen
+	expiry = int64(binary.BigEndian.Uint64(v[offset:]))
+	return in, inRate, out, outRate, supply, owner, expiry, nil
+}
+
+// DeleteOrder removes the order created in [orderID] from disk.
+func DeleteOrder(ctx context.Context, db chain.Database, orderID ids.ID) error {
+	return db.Remove(ctx, PrefixOrderKey(orderID))
+}
@@ -0,0 +1,39 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package workers
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// DurationStats accumulates a running count and sum so callers can derive an
+// average without the pool needing to retain every sample.
+type DurationStats struct {
+	Count uint64
+	Sum   time.Duration
+}
+
+// Metrics is a point-in-time snapshot of pool activity, suitable for wiring
+// into the hypersdk Prometheus registry (JobsQueued/JobsRunning as gauges,
+// JobDuration as a histogram/summary, WorkerUtilization as a gauge).
+type Metrics struct {
+	JobsQueued        uint64
+	JobsRunning       uint64
+	JobDuration       DurationStats
+	WorkerUtilization float64
+}
+
+// Metrics returns a snapshot of the pool's current activity.
+func (w *Workers) Metrics() Metrics {
+	return Metrics{
+		JobsQueued:  atomic.LoadUint64(&w.jobsQueued),
+		JobsRunning: atomic.LoadUint64(&w.jobsRunning),
+		JobDuration: DurationStats{
+			Count: atomic.LoadUint64(&w.jobDurationCount),
+			Sum:   time.Duration(atomic.LoadInt64(&w.jobDurationSum)),
+		},
+		WorkerUtilization: float64(atomic.LoadInt64(&w.busyWorkers)) / float64(w.count),
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (C) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package workers
+
+import "container/heap"
+
+// Priority is the class a Job's tasks are scheduled under in the pool's
+// task heap, where higher-priority tasks come out before lower-priority ones
+// and, within the same priority, in the order they were scheduled. The pool
+// runs one Job at a time, in the order Jobs were created, so the heap only
+// ever holds one Job's tasks and a Job's priority does not move it ahead of
+// Jobs created before it.
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+)
+
+// scheduled is a task waiting to be picked up by a worker, ordered in the
+// heap by [priority] and then by [seq] (lower runs first).
+type scheduled struct {
+	t        *task
+	priority Priority
+	seq      uint64
+}
+
+// taskHeap is a max-heap on priority (ties broken by insertion order) that
+// idle workers pull tasks from.
+type taskHeap []*scheduled
+
+func (h taskHeap) Len() int { return len(h) }
+
+func (h taskHeap) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority > h[j].priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h taskHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *taskHeap) Push(x any) {
+	*h = append(*h, x.(*scheduled))
+}
+
+func (h *taskHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
+
+var _ heap.Interface = (*taskHeap)(nil)
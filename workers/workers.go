@@ -4,7 +4,11 @@
 package workers
 
 import (
+	"container/heap"
+	"context"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Limit number of concurrent goroutines with resetable error
@@ -19,9 +23,25 @@ type Workers struct {
 	triggeredShutdown bool
 
 	// single job execution
-	err   error // requires lock
-	sg    sync.WaitGroup
-	tasks chan func() error
+	err error // requires lock
+	sg  sync.WaitGroup
+
+	// priority scheduling: idle workers wait on schedCond until [pending]
+	// has a task or the pool is stopping. Using a heap (rather than one
+	// channel per priority) keeps ordering exact even when priorities are
+	// added to or removed from use.
+	schedLock sync.Mutex
+	schedCond *sync.Cond
+	pending   taskHeap
+	seq       uint64
+	stopped   bool
+
+	// metrics: see metrics.go for the exported snapshot
+	jobsQueued       uint64
+	jobsRunning      uint64
+	jobDurationCount uint64
+	jobDurationSum   int64
+	busyWorkers      int64
 
 	// shutdown coordination
 	ackShutdown    chan struct{}
@@ -29,6 +49,15 @@ type Workers struct {
 	stoppedWorkers chan struct{}
 }
 
+// task pairs a queued function with the context (and cancellation) of the
+// Job it belongs to, so a worker can check for cancellation right before
+// running it regardless of which Job it was pulled from.
+type task struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	fn     func(ctx context.Context) error
+}
+
 // Goroutines allocate a minimum of 2KB of memory, we can save this by reusing
 // the context. This is especially useful if the goroutine stack is expanded
 // during use.
@@ -40,11 +69,11 @@ func New(workers int, maxJobs int) *Workers {
 		count: workers,
 		queue: make(chan *Job, maxJobs),
 
-		tasks:          make(chan func() error),
 		ackShutdown:    make(chan struct{}),
 		stopWorkers:    make(chan struct{}),
 		stoppedWorkers: make(chan struct{}),
 	}
+	w.schedCond = sync.NewCond(&w.schedLock)
 	w.processQueue()
 	for i := 0; i < workers; i++ {
 		w.startWorker()
@@ -52,6 +81,29 @@ func New(workers int, maxJobs int) *Workers {
 	return w
 }
 
+// schedule pushes [t] onto the priority heap and wakes one idle worker.
+func (w *Workers) schedule(t *task, priority Priority) {
+	w.schedLock.Lock()
+	w.seq++
+	heap.Push(&w.pending, &scheduled{t: t, priority: priority, seq: w.seq})
+	w.schedLock.Unlock()
+	w.schedCond.Signal()
+}
+
+// nextTask blocks until a task is available or the pool is stopping.
+func (w *Workers) nextTask() (*task, bool) {
+	w.schedLock.Lock()
+	defer w.schedLock.Unlock()
+	for len(w.pending) == 0 && !w.stopped {
+		w.schedCond.Wait()
+	}
+	if len(w.pending) == 0 {
+		return nil, false
+	}
+	s := heap.Pop(&w.pending).(*scheduled)
+	return s.t, true
+}
+
 func (w *Workers) processQueue() {
 	go func() {
 		for j := range w.queue {
@@ -60,23 +112,34 @@ func (w *Workers) processQueue() {
 			shouldShutdown := w.shouldShutdown
 			w.lock.Unlock()
 			if shouldShutdown {
+				atomic.AddUint64(&w.jobsQueued, ^uint64(0)) // decrement: no longer queued
+				j.cancel()
 				j.result <- ErrShutdown
 				continue
 			}
 
+			atomic.AddUint64(&w.jobsQueued, ^uint64(0)) // decrement: no longer queued
+			atomic.AddUint64(&w.jobsRunning, 1)
+			start := time.Now()
+
 			// Process tasks
-			for t := range j.tasks {
+			for fn := range j.tasks {
 				w.sg.Add(1)
-				w.tasks <- t
+				w.schedule(&task{ctx: j.ctx, cancel: j.cancel, fn: fn}, j.priority)
 			}
 			w.sg.Wait()
 
+			atomic.AddUint64(&w.jobsRunning, ^uint64(0)) // decrement
+			atomic.AddUint64(&w.jobDurationCount, 1)
+			atomic.AddInt64(&w.jobDurationSum, int64(time.Since(start)))
+
 			// Send result to queue and reset err
 			w.lock.Lock()
 			close(j.completed)
 			j.result <- w.err
 			w.err = nil
 			w.lock.Unlock()
+			j.cancel()
 		}
 
 		// Ensure stop returns
@@ -92,30 +155,46 @@ func (w *Workers) processQueue() {
 func (w *Workers) startWorker() {
 	go func() {
 		for {
-			select {
-			case <-w.stopWorkers:
+			t, ok := w.nextTask()
+			if !ok {
 				w.stoppedWorkers <- struct{}{}
 				return
-			case j := <-w.tasks:
-				// Check if we should even do the work
-				w.lock.RLock()
-				err := w.err
-				w.lock.RUnlock()
-				if err != nil {
-					w.sg.Done()
-					return
-				}
+			}
 
-				// Attempt to process the job
-				if err := j(); err != nil {
-					w.lock.Lock()
-					if w.err == nil {
-						w.err = err
-					}
-					w.lock.Unlock()
-				}
+			// Check if we should even do the work. The job's own ctx is
+			// cancelled as soon as any of its tasks errors (see below), so
+			// we don't need to kill the worker here too -- it stays alive
+			// for the next job.
+			w.lock.RLock()
+			err := w.err
+			w.lock.RUnlock()
+			if err != nil {
+				w.sg.Done()
+				continue
+			}
+
+			// Don't start a task whose job has already been cancelled
+			select {
+			case <-t.ctx.Done():
 				w.sg.Done()
+				continue
+			default:
 			}
+
+			// Attempt to process the job
+			atomic.AddInt64(&w.busyWorkers, 1)
+			if err := t.fn(t.ctx); err != nil {
+				w.lock.Lock()
+				if w.err == nil {
+					w.err = err
+				}
+				w.lock.Unlock()
+				// Cancel sibling tasks immediately instead of merely
+				// blocking new ones from starting.
+				t.cancel()
+			}
+			atomic.AddInt64(&w.busyWorkers, -1)
+			w.sg.Done()
 		}
 	}()
 }
@@ -130,6 +209,12 @@ func (w *Workers) Stop() {
 	<-w.ackShutdown
 	close(w.stopWorkers)
 
+	// Wake any workers parked in nextTask so they observe the stop
+	w.schedLock.Lock()
+	w.stopped = true
+	w.schedLock.Unlock()
+	w.schedCond.Broadcast()
+
 	// Wait for all workers to return
 	for i := 0; i < w.count; i++ {
 		<-w.stoppedWorkers
@@ -137,12 +222,16 @@ func (w *Workers) Stop() {
 }
 
 type Job struct {
-	tasks     chan func() error
+	ctx      context.Context
+	cancel   context.CancelFunc
+	priority Priority
+
+	tasks     chan func(ctx context.Context) error
 	completed chan struct{}
 	result    chan error
 }
 
-func (j *Job) Go(f func() error) {
+func (j *Job) Go(f func(ctx context.Context) error) {
 	j.tasks <- f
 }
 
@@ -157,24 +246,60 @@ func (j *Job) Done(f func()) {
 	}
 }
 
-func (j *Job) Wait() error {
-	return <-j.result
+// Wait blocks until the Job's tasks finish or [ctx] is cancelled, whichever
+// comes first. A caller that abandons a stuck job this way should not reuse
+// the Job afterwards.
+func (j *Job) Wait(ctx context.Context) error {
+	select {
+	case err := <-j.result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // If you don't want to block, make sure taskBacklog is greater than all
 // possible tasks you'll add.
-func (w *Workers) NewJob(taskBacklog int) (*Job, error) {
+//
+// [ctx] bounds every task submitted to the Job: workers check it before
+// running a task, and it is cancelled as soon as any task in the Job
+// returns an error, so still-running siblings can abort rather than run to
+// completion. [priority] is the class this Job's tasks are scheduled under;
+// Jobs still run one at a time in creation order (see Priority).
+func (w *Workers) NewJob(ctx context.Context, priority Priority, taskBacklog int) (*Job, error) {
 	w.lock.Lock()
 	shouldShutdown := w.shouldShutdown
 	w.lock.Unlock()
 	if shouldShutdown {
 		return nil, ErrShutdown
 	}
+	jobCtx, cancel := context.WithCancel(ctx)
 	j := &Job{
-		tasks:     make(chan func() error, taskBacklog),
+		ctx:      jobCtx,
+		cancel:   cancel,
+		priority: priority,
+
+		tasks:     make(chan func(ctx context.Context) error, taskBacklog),
 		completed: make(chan struct{}),
 		result:    make(chan error, 1),
 	}
+	atomic.AddUint64(&w.jobsQueued, 1)
 	w.queue <- j
 	return j, nil
 }
+
+// Submit runs every task in [taskBacklog] as part of a single Job at
+// [priority] and blocks until they finish or [ctx] is cancelled. As soon as
+// any task returns an error, the Job's context is cancelled so the
+// remaining tasks can abort instead of running to completion.
+func (w *Workers) Submit(ctx context.Context, priority Priority, taskBacklog []func(ctx context.Context) error) error {
+	j, err := w.NewJob(ctx, priority, len(taskBacklog))
+	if err != nil {
+		return err
+	}
+	for _, t := range taskBacklog {
+		j.Go(t)
+	}
+	j.Done(nil)
+	return j.Wait(ctx)
+}